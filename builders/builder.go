@@ -11,12 +11,18 @@ import (
 type DocumentBuilder interface {
 	// SetProjectName sets the name of the project, to be used in document titles.
 	SetProjectName(name string)
-	// // SetFileTree is a planned feature to include a file tree overview in the document.
-	// SetFileTree(tree string)
+	// SetFileTree sets the file tree overview rendered at the top of the document.
+	SetFileTree(tree *templates.TreeNode)
 	// SetSummary sets the a README summary
-	SetSummary(summary string)
+	SetSummary(summary templates.FileData)
 	// AddFile adds a file's data to the builder for inclusion in the final document.
 	AddFile(file templates.FileData)
+	// SetSortBy sets how AddFile'd files are ordered before Build, one of
+	// "path" (default), "size", or "lang". See templates.SortFiles.
+	SetSortBy(by string)
+	// SetLoader overrides the templates.Loader used by Build. Passing nil
+	// restores the default embedded-template behaviour.
+	SetLoader(loader templates.Loader)
 	// Build uses a template format to construct the final document and returns it as an io.Reader.
 	Build() (io.Reader, error)
 }
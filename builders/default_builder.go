@@ -9,8 +9,11 @@ import (
 // a Markdown document from the provided file data.
 type DefaultBuilder struct {
 	projectName    string
-	projectSummary string
+	projectSummary templates.FileData
 	files          []templates.FileData
+	loader         templates.Loader
+	fileTree       *templates.TreeNode
+	sortBy         string
 }
 
 // NewDefaultBuilder creates and returns a new DefaultBuilder instance.
@@ -23,12 +26,8 @@ func (b *DefaultBuilder) SetProjectName(name string) {
 	b.projectName = name
 }
 
-// // SetFileTree is a placeholder for a future feature.
-// func (b *DefaultBuilder) SetFileTree(tree string) {
-// 	// TODO: Implement file tree generation and inclusion.
-// }
-
-func (b *DefaultBuilder) SetSummary(summary string) {
+// SetSummary sets the README summary shown at the top of the document.
+func (b *DefaultBuilder) SetSummary(summary templates.FileData) {
 	b.projectSummary = summary
 }
 
@@ -37,13 +36,36 @@ func (b *DefaultBuilder) AddFile(file templates.FileData) {
 	b.files = append(b.files, file)
 }
 
+// SetLoader overrides the templates.Loader used by Build.
+func (b *DefaultBuilder) SetLoader(loader templates.Loader) {
+	b.loader = loader
+}
+
+// SetFileTree sets the file tree overview rendered at the top of the document.
+func (b *DefaultBuilder) SetFileTree(tree *templates.TreeNode) {
+	b.fileTree = tree
+}
+
+// SetSortBy sets how AddFile'd files are ordered before Build.
+func (b *DefaultBuilder) SetSortBy(by string) {
+	b.sortBy = by
+}
+
 // Build generates the final document with template.
 // It returns the generated document as an io.Reader.
 func (b *DefaultBuilder) Build() (io.Reader, error) {
+	templates.SortFiles(b.files, b.sortBy)
+
+	var fileTree string
+	if b.fileTree != nil {
+		fileTree = b.fileTree.Render()
+	}
+
 	templateData := templates.TemplateData{
 		ProjectName:    b.projectName,
 		ProjectSummary: b.projectSummary,
+		FileTree:       fileTree,
 		Files:          b.files,
 	}
-	return templates.ExecuteTemplate(templates.Default, templateData)
+	return templates.ExecuteTemplate(b.loader, templates.Default, templateData)
 }
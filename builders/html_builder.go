@@ -0,0 +1,195 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	htmlesc "html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"pizza-nz/project-struct-cli/templates"
+)
+
+// HTMLBuilder is a concrete implementation of DocumentBuilder that emits a
+// single self-contained HTML document with Chroma syntax-highlighted source
+// and a collapsible table-of-contents sidebar linking to each file.
+type HTMLBuilder struct {
+	projectName    string
+	projectSummary templates.FileData
+	files          []templates.FileData
+	loader         templates.Loader
+	theme          string
+	fileTree       *templates.TreeNode
+	sortBy         string
+}
+
+// NewHTMLBuilder creates and returns a new HTMLBuilder instance using the
+// "monokai" Chroma style by default.
+func NewHTMLBuilder() *HTMLBuilder {
+	return &HTMLBuilder{theme: "monokai"}
+}
+
+// SetProjectName stores the project name for use in the template.
+func (b *HTMLBuilder) SetProjectName(name string) {
+	b.projectName = name
+}
+
+// SetSummary sets the README summary shown at the top of the document.
+func (b *HTMLBuilder) SetSummary(summary templates.FileData) {
+	b.projectSummary = summary
+}
+
+// AddFile appends file data to the internal slice.
+func (b *HTMLBuilder) AddFile(file templates.FileData) {
+	b.files = append(b.files, file)
+}
+
+// SetLoader overrides the templates.Loader used by Build.
+func (b *HTMLBuilder) SetLoader(loader templates.Loader) {
+	b.loader = loader
+}
+
+// SetFileTree sets the file tree overview rendered at the top of the document.
+func (b *HTMLBuilder) SetFileTree(tree *templates.TreeNode) {
+	b.fileTree = tree
+}
+
+// SetSortBy sets how AddFile'd files are ordered before Build.
+func (b *HTMLBuilder) SetSortBy(by string) {
+	b.sortBy = by
+}
+
+// SetTheme sets the Chroma style used to highlight source code (e.g.
+// "monokai", "github"). An empty theme leaves the current theme (monokai by
+// default) unchanged; an unknown theme falls back to Chroma's fallback style.
+func (b *HTMLBuilder) SetTheme(theme string) {
+	if theme != "" {
+		b.theme = theme
+	}
+}
+
+// highlightedFile is the per-file data handed to the HTML template: the
+// source already rendered as Chroma HTML, plus enough metadata to build the
+// table-of-contents sidebar and deep-link anchors.
+type highlightedFile struct {
+	Path     string
+	AnchorID string
+	Language string
+	HTML     string
+}
+
+// Build renders every added file through Chroma and executes the HTML
+// template with the resulting markup and an inlined stylesheet.
+func (b *HTMLBuilder) Build() (io.Reader, error) {
+	templates.SortFiles(b.files, b.sortBy)
+
+	style := styles.Get(b.theme)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	formatter := html.New(html.WithLineNumbers(true), html.WithClasses(true), html.ClassPrefix("chroma-"))
+
+	var cssBuf bytes.Buffer
+	if err := formatter.WriteCSS(&cssBuf, style); err != nil {
+		return nil, fmt.Errorf("could not generate highlight CSS: %w", err)
+	}
+
+	anchors := make(map[string]int, len(b.files))
+	highlighted := make([]highlightedFile, 0, len(b.files))
+	for _, file := range b.files {
+		lexer := lexerFor(file)
+
+		iterator, err := lexer.Tokenise(nil, file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("could not tokenise %s: %w", file.Path, err)
+		}
+
+		var fileBuf bytes.Buffer
+		if err := formatter.Format(&fileBuf, style, iterator); err != nil {
+			return nil, fmt.Errorf("could not render %s: %w", file.Path, err)
+		}
+
+		highlighted = append(highlighted, highlightedFile{
+			Path:     htmlesc.EscapeString(file.Path),
+			AnchorID: uniqueAnchorID(file.Path, anchors),
+			Language: lexer.Config().Name,
+			HTML:     fileBuf.String(),
+		})
+	}
+
+	var fileTree string
+	if b.fileTree != nil {
+		fileTree = htmlesc.EscapeString(b.fileTree.Render())
+	}
+
+	summary := b.projectSummary
+	summary.Content = htmlesc.EscapeString(summary.Content)
+
+	templateData := struct {
+		ProjectName    string
+		ProjectSummary templates.FileData
+		CSS            string
+		FileTree       string
+		Files          []highlightedFile
+	}{
+		ProjectName:    htmlesc.EscapeString(b.projectName),
+		ProjectSummary: summary,
+		CSS:            cssBuf.String(),
+		FileTree:       fileTree,
+		Files:          highlighted,
+	}
+
+	return templates.ExecuteTemplate(b.loader, templates.HTML, templateData)
+}
+
+// anchorSlugRE matches runs of characters that aren't safe to keep verbatim
+// in an HTML id attribute, so slugifyAnchor can collapse them to a single "-".
+var anchorSlugRE = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// slugifyAnchor turns a file path into a stable, URL-friendly anchor id, so
+// deep links keep working across regenerations that add, remove or reorder
+// files — unlike a positional index, the id only changes if the path does.
+func slugifyAnchor(path string) string {
+	slug := strings.Trim(anchorSlugRE.ReplaceAllString(path, "-"), "-")
+	slug = strings.ToLower(slug)
+	if slug == "" {
+		slug = "file"
+	}
+	return "file-" + slug
+}
+
+// uniqueAnchorID returns a slug anchor for path, disambiguating it with a
+// numeric suffix if an earlier file already produced the same slug (e.g.
+// two paths that only differ in characters the slug collapses).
+func uniqueAnchorID(path string, seen map[string]int) string {
+	id := slugifyAnchor(path)
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		id = fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
+
+// lexerFor resolves a Chroma lexer for file, preferring a match on its
+// filename, then its detected Language, then content analysis, so unknown
+// but detectable languages still get highlighted.
+func lexerFor(file templates.FileData) chroma.Lexer {
+	lexer := lexers.Match(file.Path)
+	if lexer == nil && file.Language != "" {
+		lexer = lexers.Get(file.Language)
+	}
+	if lexer == nil {
+		lexer = lexers.Analyse(file.Content)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return chroma.Coalesce(lexer)
+}
@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"pizza-nz/project-struct-cli/templates"
+)
+
+// TestUniqueAnchorIDStableAcrossReordering verifies that a file's HTML
+// anchor is derived from its path rather than its position, so external
+// deep links keep working when the file set changes (e.g. due to ignore
+// rules or a new file being added).
+func TestUniqueAnchorIDStableAcrossReordering(t *testing.T) {
+	before := uniqueAnchorID("cmd/main.go", map[string]int{})
+
+	seen := map[string]int{}
+	uniqueAnchorID("README.md", seen)
+	after := uniqueAnchorID("cmd/main.go", seen)
+
+	if before != after {
+		t.Fatalf("anchor for cmd/main.go changed when a preceding file was added: %q != %q", before, after)
+	}
+}
+
+// TestUniqueAnchorIDDisambiguatesCollisions verifies that two paths whose
+// slugs collapse to the same string still get distinct anchors.
+func TestUniqueAnchorIDDisambiguatesCollisions(t *testing.T) {
+	seen := map[string]int{}
+	a := uniqueAnchorID("a/b.go", seen)
+	b := uniqueAnchorID("a-b.go", seen)
+
+	if a == b {
+		t.Fatalf("expected distinct anchors for colliding slugs, got %q for both", a)
+	}
+}
+
+// TestHTMLBuilderEscapesUntrustedInput verifies that file paths, the
+// project name and the README summary can't break out of the generated
+// markup: since html.tmpl is parsed with text/template (to share the same
+// Loader/FuncMap as the Markdown formats), Build must escape these values
+// itself before they reach the template.
+func TestHTMLBuilderEscapesUntrustedInput(t *testing.T) {
+	b := NewHTMLBuilder()
+	b.SetProjectName(`"><script>alert(1)</script>`)
+	b.SetSummary(templates.FileData{Content: `<img src=x onerror=alert(1)>`})
+	b.AddFile(templates.FileData{
+		Path:     `"><script>alert(2)</script>.go`,
+		Content:  "package main\n",
+		Language: "go",
+	})
+
+	doc, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	out, err := io.ReadAll(doc)
+	if err != nil {
+		t.Fatalf("could not read Build output: %v", err)
+	}
+
+	if strings.Contains(string(out), "<script>") {
+		t.Fatalf("untrusted input injected a live <script> tag:\n%s", out)
+	}
+	if !strings.Contains(string(out), "&lt;script&gt;") {
+		t.Fatalf("expected the script payload to appear escaped, got:\n%s", out)
+	}
+}
@@ -1,7 +1,12 @@
 package builder
 
 import (
+	"fmt"
 	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"pizza-nz/project-struct-cli/templates"
 )
 
@@ -11,6 +16,13 @@ type LLMBuilder struct {
 	projectName    string
 	projectSummary templates.FileData
 	files          []templates.FileData
+	loader         templates.Loader
+	fileTree       *templates.TreeNode
+	sortBy         string
+
+	tokenBudget   int
+	model         string
+	priorityGlobs []string
 }
 
 // NewLLMBuilder creates and returns a new LLMBuilder instance.
@@ -23,10 +35,10 @@ func (b *LLMBuilder) SetProjectName(name string) {
 	b.projectName = name
 }
 
-// // SetFileTree is a placeholder for a future feature.
-// func (b *LLMBuilder) SetFileTree(tree string) {
-// 	// TODO: Implement file tree generation and inclusion.
-// }
+// SetFileTree sets the file tree overview rendered at the top of the document.
+func (b *LLMBuilder) SetFileTree(tree *templates.TreeNode) {
+	b.fileTree = tree
+}
 
 func (b *LLMBuilder) SetSummary(summary templates.FileData) {
 	b.projectSummary = summary
@@ -37,13 +49,104 @@ func (b *LLMBuilder) AddFile(file templates.FileData) {
 	b.files = append(b.files, file)
 }
 
+// SetLoader overrides the templates.Loader used by Build.
+func (b *LLMBuilder) SetLoader(loader templates.Loader) {
+	b.loader = loader
+}
+
+// SetSortBy sets how AddFile'd files are ordered before Build.
+func (b *LLMBuilder) SetSortBy(by string) {
+	b.sortBy = by
+}
+
+// WithTokenBudget configures Build to keep the document within n tokens,
+// as estimated for model (e.g. 128000 for "gpt-4o"). When the accepted
+// files don't fit, Build reorders them by priority (see SetPriorityGlobs)
+// and replaces the lowest-priority overflow with a truncation notice
+// instead of dropping them from the listing entirely. A budget <= 0
+// disables the check, which is the default.
+func (b *LLMBuilder) WithTokenBudget(n int, model string) {
+	b.tokenBudget = n
+	b.model = model
+}
+
+// SetPriorityGlobs sets filepath.Match patterns (e.g. "cmd/*", "README.md")
+// whose matches are kept first when WithTokenBudget has to truncate.
+func (b *LLMBuilder) SetPriorityGlobs(globs []string) {
+	b.priorityGlobs = globs
+}
+
 // Build generates the final document with template.
 // It returns the generated document as an io.Reader.
 func (b *LLMBuilder) Build() (io.Reader, error) {
+	templates.SortFiles(b.files, b.sortBy)
+
+	files := b.files
+	if b.tokenBudget > 0 {
+		files = applyTokenBudget(files, b.tokenBudget, b.priorityGlobs)
+	}
+
+	var fileTree string
+	if b.fileTree != nil {
+		fileTree = b.fileTree.Render()
+	}
+
 	templateData := templates.TemplateData{
 		ProjectName:    b.projectName,
 		ProjectSummary: b.projectSummary,
-		Files:          b.files,
+		FileTree:       fileTree,
+		Files:          files,
+	}
+	return templates.ExecuteTemplate(b.loader, templates.LLM, templateData)
+}
+
+// applyTokenBudget reorders files by priority (highest first, stable
+// within a tier) and returns the result in that order: files up to budget
+// tokens are returned unchanged, the rest have their Content replaced with
+// a short truncation notice so the listing still records that they exist.
+func applyTokenBudget(files []templates.FileData, budget int, priorityGlobs []string) []templates.FileData {
+	ordered := make([]templates.FileData, len(files))
+	copy(ordered, files)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return filePriority(ordered[i].Path, priorityGlobs) < filePriority(ordered[j].Path, priorityGlobs)
+	})
+
+	result := make([]templates.FileData, len(ordered))
+	var used int
+	for i, file := range ordered {
+		if used+file.Tokens <= budget {
+			result[i] = file
+			used += file.Tokens
+			continue
+		}
+		result[i] = templates.FileData{
+			Path:     file.Path,
+			Language: file.Language,
+			Tokens:   file.Tokens,
+			Content:  fmt.Sprintf("// truncated: omitted to stay within the %d token budget (%d tokens)", budget, file.Tokens),
+		}
+	}
+	return result
+}
+
+// filePriority scores path for ordering under a token budget: lower sorts
+// first. Files matching priorityGlobs and conventional entrypoints
+// (main.go, README.md) come first, tests come last, everything else is in
+// between.
+func filePriority(path string, priorityGlobs []string) int {
+	for _, glob := range priorityGlobs {
+		if ok, _ := filepath.Match(glob, path); ok {
+			return 0
+		}
+	}
+
+	base := filepath.Base(path)
+	switch {
+	case base == "main.go" || base == "README.md":
+		return 0
+	case strings.HasSuffix(base, "_test.go") || strings.Contains(filepath.ToSlash(path), "/test/"):
+		return 2
+	default:
+		return 1
 	}
-	return templates.ExecuteTemplate(templates.LLM, templateData)
 }
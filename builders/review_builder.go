@@ -11,6 +11,9 @@ type ReviewBuilder struct {
 	projectName    string
 	projectSummary templates.FileData
 	files          []templates.FileData
+	loader         templates.Loader
+	fileTree       *templates.TreeNode
+	sortBy         string
 }
 
 // ReviewFileData will be used internally by the builder for the review template
@@ -42,7 +45,24 @@ func (b *ReviewBuilder) AddFile(file templates.FileData) {
 	b.files = append(b.files, file)
 }
 
+// SetLoader overrides the templates.Loader used by Build.
+func (b *ReviewBuilder) SetLoader(loader templates.Loader) {
+	b.loader = loader
+}
+
+// SetFileTree sets the file tree overview rendered at the top of the document.
+func (b *ReviewBuilder) SetFileTree(tree *templates.TreeNode) {
+	b.fileTree = tree
+}
+
+// SetSortBy sets how AddFile'd files are ordered before Build.
+func (b *ReviewBuilder) SetSortBy(by string) {
+	b.sortBy = by
+}
+
 func (b *ReviewBuilder) Build() (io.Reader, error) {
+	templates.SortFiles(b.files, b.sortBy)
+
 	var reviewFiles []ReviewFileData
 	for _, file := range b.files {
 		var numberedLines []string
@@ -59,15 +79,22 @@ func (b *ReviewBuilder) Build() (io.Reader, error) {
 		})
 	}
 
+	var fileTree string
+	if b.fileTree != nil {
+		fileTree = b.fileTree.Render()
+	}
+
 	templateData := struct {
 		ProjectName    string
 		ProjectSummary templates.FileData
+		FileTree       string
 		Files          []ReviewFileData
 	}{
 		ProjectName:    b.projectName,
 		ProjectSummary: b.projectSummary,
+		FileTree:       fileTree,
 		Files:          reviewFiles,
 	}
 
-	return templates.ExecuteTemplate(templates.Review, templateData)
+	return templates.ExecuteTemplate(b.loader, templates.Review, templateData)
 }
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
+
 	builder "pizza-nz/project-struct-cli/builders"
 	"pizza-nz/project-struct-cli/templates"
-	"strings"
-
-	ignore "github.com/sabhiram/go-gitignore"
 )
 
 // A set of common binary and archive file extensions to always ignore.
@@ -21,45 +25,59 @@ var binaryExts = map[string]struct{}{
 	".pdf": {}, ".doc": {}, ".docx": {}, ".xls": {}, ".xlsx": {}, ".ppt": {}, ".pptx": {},
 }
 
+// sniffSize is how many leading bytes of a file processCandidates reads
+// to decide whether it's binary, when the extension alone doesn't tell us.
+const sniffSize = 512
+
 // Generator is responsible for walking a directory structure, reading files,
 // and passing their data to a DocumentBuilder.
 type Generator struct {
 	builder          builder.DocumentBuilder
-	cliIgnoreMatcher ignore.IgnoreParser
 	srcDir           string
 	maxSizeBytes     int64
 	totalSizeLimit   int64
 	currentTotalSize int64
 
-	matcherCache map[string]ignore.IgnoreParser
+	cliIgnorePatterns string
+	ignoreFile        string
+	debugIgnore       bool
+	ignore            *ignoreEngine
+
+	buildTree bool
+	sortBy    string
+	files     []templates.FileData
+
+	tokenizer templates.Tokenizer
+	jobs      int
 }
 
 // Option is a function type used to configure a Generator. This follows the
 // "Functional Options" pattern, allowing for flexible and clear configuration.
 type Option func(*Generator)
 
-// // WithGitIgnore returns an Option that configures the Generator to use a
-// // .gitignore file for filtering which files and directories to ignore.
-// func WithGitIgnore(path string) Option {
-// 	return func(g *Generator) {
-// 		matcher, err := ignore.CompileIgnoreFile(path)
-// 		// If the .gitignore file doesn't exist or has errors, we simply
-// 		// proceed without an ignore matcher.
-// 		if err == nil {
-// 			g.gitIgnoreMatcher = matcher
-// 		}
-// 	}
-// }
-
+// WithCliIgnore returns an Option that adds a legacy comma-separated list of
+// ignore patterns to the Generator's rule list. These are layered last, so
+// they take precedence over any .gitignore or --ignore-file rule.
 func WithCliIgnore(patterns string) Option {
 	return func(g *Generator) {
-		if patterns == "" {
-			return // Do nothing if empty
-		}
+		g.cliIgnorePatterns = patterns
+	}
+}
 
-		lines := strings.Split(patterns, ",")
-		matcher := ignore.CompileIgnoreLines(lines...)
-		g.cliIgnoreMatcher = matcher
+// WithIgnoreFile returns an Option that layers an additional ignore file
+// (e.g. .projectstructignore) above the discovered .gitignore files, below
+// the CLI patterns.
+func WithIgnoreFile(path string) Option {
+	return func(g *Generator) {
+		g.ignoreFile = path
+	}
+}
+
+// WithDebugIgnore returns an Option that logs which rule (source file and
+// line) decided the fate of every path the ignore engine skips.
+func WithDebugIgnore(enabled bool) Option {
+	return func(g *Generator) {
+		g.debugIgnore = enabled
 	}
 }
 
@@ -91,127 +109,255 @@ func WithTotalSizeLimit(mb int64) Option {
 	}
 }
 
+// WithFileTree returns an Option that, when enabled, builds a templates.TreeNode
+// from the accepted files once Walk completes and passes it to the builder
+// via SetFileTree.
+func WithFileTree(enabled bool) Option {
+	return func(g *Generator) {
+		g.buildTree = enabled
+	}
+}
+
+// WithSortBy returns an Option that sets how accepted files are ordered
+// before the builder renders them, one of "path" (default), "size", or
+// "lang". See templates.SortFiles.
+func WithSortBy(by string) Option {
+	return func(g *Generator) {
+		g.sortBy = by
+	}
+}
+
+// WithJobs returns an Option that sets how many worker goroutines Walk uses
+// to read and process files concurrently. n <= 0 falls back to
+// runtime.NumCPU().
+func WithJobs(n int) Option {
+	return func(g *Generator) {
+		g.jobs = n
+	}
+}
+
 // NewGenerator creates a new Generator and applies all the provided functional options.
 func NewGenerator(opts ...Option) *Generator {
-	g := &Generator{
-		matcherCache: make(map[string]ignore.IgnoreParser),
-	}
+	g := &Generator{}
 	for _, opt := range opts {
 		opt(g)
 	}
+	if g.tokenizer == nil {
+		g.tokenizer = templates.HeuristicTokenizer{}
+	}
+	if g.jobs <= 0 {
+		g.jobs = runtime.NumCPU()
+	}
 	return g
 }
 
-// Walk starts the process of walking the source directory tree.
+// candidate is a regular file accepted by the walk that still needs to be
+// read and processed by a worker.
+type candidate struct {
+	path string
+	info os.FileInfo
+}
+
+// Walk scans the source directory tree with one goroutine walking the
+// filesystem and feeding accepted regular files to a pool of g.jobs worker
+// goroutines, which read, binary-sniff and language/token-tag each file
+// concurrently. A single collector goroutine gathers every result, then
+// applies the total-size budget and calls g.builder.AddFile in stable path
+// order rather than raw worker-arrival order, so which files make the cut
+// under --max-total doesn't vary between runs of the same tree.
 func (g *Generator) Walk() error {
 	fmt.Printf("Scanning directory: %s\n", g.srcDir)
-	return filepath.WalkDir(g.srcDir, g.processPath)
-}
 
-// findMatcherForDir walks up from a given directory to find the applicalbe .gitingore file.
-// It uses a cache to avoid redundant file system lookup.
-func (g *Generator) findMatcherForDir(dir string) ignore.IgnoreParser {
-	// 1. Check if we have already calculated the matcher for this directory.
-	if matcher, exists := g.matcherCache[dir]; exists {
-		return matcher // Return the cached value (can be a matcher or nil)
+	engine, err := newIgnoreEngine(g.srcDir, g.ignoreFile, g.cliIgnorePatterns, g.debugIgnore)
+	if err != nil {
+		return fmt.Errorf("could not build ignore rules: %w", err)
 	}
+	g.ignore = engine
 
-	// 2. Look for a .gitignore in the current directory.
-	ignorePath := filepath.Join(dir, ".gitignore")
-	if _, err := os.Stat(ignorePath); err == nil {
-		// Found a .gitignore here. Compile it, cache it, and return it.
-		matcher, err := ignore.CompileIgnoreFile(ignorePath)
-		if err == nil {
-			g.matcherCache[dir] = matcher
-			return matcher
-		}
+	g.builder.SetSortBy(g.sortBy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	candidates := make(chan candidate, g.jobs)
+	results := make(chan templates.FileData, g.jobs)
+
+	var walkErr error
+	go func() {
+		defer close(candidates)
+		walkErr = filepath.WalkDir(g.srcDir, g.walkCandidates(ctx, candidates))
+	}()
+
+	var workers sync.WaitGroup
+	workers.Add(g.jobs)
+	for i := 0; i < g.jobs; i++ {
+		go func() {
+			defer workers.Done()
+			g.processCandidates(candidates, results)
+		}()
 	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
 
-	// 3. If we are at the root of the scan, there are no more parents to check.
-	if dir == g.srcDir || dir == "." || dir == "/" {
-		g.matcherCache[dir] = nil // Cache nil to show we've checked and found nothing.
-		return nil
+	var collected []templates.FileData
+	for file := range results {
+		collected = append(collected, file)
 	}
 
-	// 4. If no .gitignore was found here, the correct rules are the same as the parent's.
-	// We recursively call the function for the parent directory.
-	parentDir := filepath.Dir(dir)
-	parentMatcher := g.findMatcherForDir(parentDir)
+	if walkErr != nil {
+		return walkErr
+	}
 
-	// Cache the parent's matcher for the current directory to speed up future lookups.
-	g.matcherCache[dir] = parentMatcher
-	return parentMatcher
-}
+	// Order is fixed before the budget check runs, so which files fall on
+	// either side of --max-total is reproducible regardless of how the
+	// workers happened to finish.
+	templates.SortFiles(collected, "path")
 
-// processPath is the callback function for filepath.WalkDir. It is called
-// for every file and directory in the source tree.
-func (g *Generator) processPath(path string, d os.DirEntry, err error) error {
-	if err != nil {
-		return err
+	var sizeErr error
+	for _, file := range collected {
+		if g.totalSizeLimit > 0 && g.currentTotalSize+int64(len(file.Content)) > g.totalSizeLimit {
+			sizeErr = fmt.Errorf("total size limit of %.2f MB exceeded", float64(g.totalSizeLimit)/(1024*1024))
+			break
+		}
+		if g.totalSizeLimit > 0 {
+			g.currentTotalSize += int64(len(file.Content))
+		}
+
+		g.builder.AddFile(file)
+		if g.buildTree {
+			g.files = append(g.files, file)
+		}
 	}
 
-	// Always skip .git directories.
-	if d.IsDir() && d.Name() == ".git" {
-		return filepath.SkipDir
+	if sizeErr != nil {
+		return sizeErr
 	}
 
-	dir := filepath.Dir(path)
-	gitMatcher := g.findMatcherForDir(dir)
+	if g.buildTree {
+		// The tree always renders directory-first/lexicographic like
+		// tree(1), independent of --sort, which only orders the document's
+		// file listing.
+		templates.SortFiles(g.files, "path")
+		tree := templates.NewFileTree(filepath.Base(g.srcDir), g.files)
+		g.builder.SetFileTree(tree)
+	}
 
-	// Check if the path should be ignored based on .gitignore or cli ignore rules.
-	if (gitMatcher != nil && gitMatcher.MatchesPath(path)) ||
-		(g.cliIgnoreMatcher != nil && g.cliIgnoreMatcher.MatchesPath(path)) {
-		// If a directory is ignored, skip it entirely.
-		if d.IsDir() {
+	return nil
+}
+
+// walkCandidates returns the filepath.WalkDir callback that applies ignore
+// rules, directory pruning and the cheap extension/size checks, then
+// queues every accepted regular file onto candidates for the worker pool.
+// It stops early once ctx is cancelled; the total size limit is applied
+// only after every candidate has been processed, so it does not cancel ctx.
+func (g *Generator) walkCandidates(ctx context.Context, candidates chan<- candidate) fs.WalkDirFunc {
+	return func(path string, d os.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+
+		// Always skip .git directories.
+		if d.IsDir() && d.Name() == ".git" {
 			return filepath.SkipDir
 		}
-		// If it's an ignored file, just skip this entry.
-		return nil
-	}
 
-	if !d.IsDir() {
+		if g.ignore.Match(path) {
+			// A directory can only be pruned outright if no negation rule
+			// further down the list could re-include one of its descendants;
+			// otherwise we must keep walking and let per-path matching decide.
+			if d.IsDir() && !g.ignore.MayReincludeDescendant(path) {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() {
+				return nil
+			}
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
 		ext := filepath.Ext(path)
 		if _, exists := binaryExts[ext]; exists {
 			log.Printf("Skipping binary/archive file: %s", path)
-			return nil // Skip this file and continue the walk.
+			return nil
 		}
 
-		// File info to check size
 		info, err := d.Info()
 		if err != nil {
 			log.Printf("Could not get file info for %s: %v", path, err)
-			return nil // Skip if we can't get info
+			return nil
 		}
 		if g.maxSizeBytes > 0 && info.Size() > g.maxSizeBytes {
 			log.Printf("Skipping large file: %s (size: %.2f KB)", path, float64(info.Size())/1024.0)
 			return nil
 		}
 
-		if g.totalSizeLimit > 0 && (g.currentTotalSize+info.Size() > g.totalSizeLimit) {
-			return fmt.Errorf("total size limit of %.2f MB exceeded", float64(g.totalSizeLimit)/(1024*1024))
+		select {
+		case candidates <- candidate{path: path, info: info}:
+		case <-ctx.Done():
+			return filepath.SkipAll
 		}
+		return nil
+	}
+}
 
-		content, readErr := os.ReadFile(path)
-		if readErr != nil {
-			// Log the error but don't stop the whole process.
-			log.Printf("Could not read file %s: %v", path, readErr)
-			return nil
+// processCandidates is a worker goroutine body: it reads, binary-sniffs and
+// tags each candidate with language and token count, sending accepted files
+// to results. It runs until candidates is closed.
+func (g *Generator) processCandidates(candidates <-chan candidate, results chan<- templates.FileData) {
+	for c := range candidates {
+		binary, err := isBinary(c.path)
+		if err != nil {
+			log.Printf("Could not sniff file %s: %v", c.path, err)
+			continue
+		}
+		if binary {
+			log.Printf("Skipping binary file (content sniff): %s", c.path)
+			continue
 		}
 
-		g.currentTotalSize += info.Size()
+		content, err := os.ReadFile(c.path)
+		if err != nil {
+			// Log the error but don't stop the whole process.
+			log.Printf("Could not read file %s: %v", c.path, err)
+			continue
+		}
 
 		// Get the file path relative to the source directory for cleaner output.
-		relativePath, err := filepath.Rel(g.srcDir, path)
+		relativePath, err := filepath.Rel(g.srcDir, c.path)
 		if err != nil {
-			relativePath = path // Fallback to the full path on error.
+			relativePath = c.path // Fallback to the full path on error.
 		}
 
-		file := templates.FileData{
+		results <- templates.FileData{
 			Path:     relativePath,
 			Content:  string(content),
 			Language: getFileLanguage(relativePath),
+			Tokens:   g.tokenizer.CountTokens(string(content)),
 		}
-		g.builder.AddFile(file)
 	}
-	return nil
+}
+
+// isBinary reports whether path looks like a binary file by checking its
+// first sniffSize bytes for a NUL byte, the same heuristic git itself uses.
+// This catches binary files whose extension isn't in binaryExts.
+func isBinary(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffSize)
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return bytes.IndexByte(buf[:n], 0) != -1, nil
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	builder "pizza-nz/project-struct-cli/builders"
+)
+
+// TestWalkMaxTotalDeterministic guards against the --max-total budget being
+// applied in worker-arrival order: which files land on either side of the
+// cutoff must not depend on how a run's g.jobs workers happened to finish.
+func TestWalkMaxTotalDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	const n = 100
+	for i := 0; i < n; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%02d.txt", i))
+		if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+			t.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+
+	var firstFiles []string
+	for run := 0; run < 5; run++ {
+		g := NewGenerator(
+			WithBuilder(builder.NewDefaultBuilder()),
+			WithSrcDir(dir),
+			WithFileTree(true),
+			WithJobs(8),
+		)
+		g.totalSizeLimit = 500 // bytes: enough for half the files.
+
+		err := g.Walk()
+		if err == nil {
+			t.Fatalf("run %d: expected the total size limit to be exceeded", run)
+		}
+
+		var files []string
+		for _, f := range g.files {
+			files = append(files, f.Path)
+		}
+
+		if run == 0 {
+			firstFiles = files
+			continue
+		}
+		if len(files) != len(firstFiles) {
+			t.Fatalf("run %d: collected %d files, run 0 collected %d", run, len(files), len(firstFiles))
+		}
+		for i := range files {
+			if files[i] != firstFiles[i] {
+				t.Fatalf("run %d: file %d was %q, run 0 had %q", run, i, files[i], firstFiles[i])
+			}
+		}
+	}
+}
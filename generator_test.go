@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	builder "pizza-nz/project-struct-cli/builders"
+)
+
+// buildSyntheticTree creates n small Go files spread across a handful of
+// subdirectories under dir, simulating a large repository for benchmarking
+// Walk's concurrent read/process pipeline.
+func buildSyntheticTree(tb testing.TB, dir string, n int) {
+	tb.Helper()
+	const dirsPerLevel = 20
+	for i := 0; i < n; i++ {
+		sub := filepath.Join(dir, fmt.Sprintf("pkg%d", i%dirsPerLevel))
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			tb.Fatalf("could not create %s: %v", sub, err)
+		}
+		path := filepath.Join(sub, fmt.Sprintf("file%d.go", i))
+		content := fmt.Sprintf("package pkg%d\n\nfunc F%d() int { return %d }\n", i%dirsPerLevel, i, i)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			tb.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+}
+
+// BenchmarkGeneratorWalk guards the concurrent Walk pipeline against
+// performance regressions on a large (10k file) synthetic repository.
+func BenchmarkGeneratorWalk(b *testing.B) {
+	dir := b.TempDir()
+	buildSyntheticTree(b, dir, 10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := NewGenerator(
+			WithBuilder(builder.NewDefaultBuilder()),
+			WithSrcDir(dir),
+		)
+		if err := g.Walk(); err != nil {
+			b.Fatalf("Walk failed: %v", err)
+		}
+	}
+}
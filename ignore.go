@@ -0,0 +1,213 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// ruleSource records where a single compiled ignore-pattern line came from,
+// so --debug-ignore can explain which rule decided a path's fate.
+type ruleSource struct {
+	origin string // path to the .gitignore file, "--ignore-file", or "--ignore"
+	line   int    // 1-based line number within origin
+	text   string
+}
+
+// rule is a single ignore-pattern line compiled on its own, so the engine
+// can tell exactly which line last matched a path instead of relying on
+// whichever positive match the underlying library happened to remember.
+type rule struct {
+	source  ruleSource
+	pattern string // the glob text, with any leading "!" already stripped
+	negate  bool
+	matcher *ignore.GitIgnore // compiled from pattern
+
+	// originDir is the directory a .gitignore rule's patterns are rooted
+	// at (its own containing directory), so the rule only ever applies to
+	// paths under that directory and is matched relative to it — the same
+	// scoping git itself applies. It is empty for --ignore-file and
+	// --ignore rules, which apply anywhere under the scan root.
+	originDir string
+}
+
+// ignoreEngine layers ignore patterns from every applicable .gitignore file
+// found walking down from the scan root, an optional --ignore-file, and CLI
+// patterns into a single ordered rule list. Rules are evaluated in the
+// order supplied (root-most .gitignore first, CLI patterns last), so a
+// later "!pattern" can re-include something an earlier rule excluded —
+// the same precedence git itself applies within one .gitignore file.
+type ignoreEngine struct {
+	rules      []rule
+	debugPaths bool
+}
+
+// newIgnoreEngine builds the layered rule engine for a scan rooted at
+// srcDir. ignoreFile, if non-empty, is an additional (e.g.
+// .projectstructignore) file layered above the discovered .gitignore files.
+// cliPatterns is the legacy comma-separated --ignore flag and is layered
+// last, giving it the final say.
+func newIgnoreEngine(srcDir, ignoreFile, cliPatterns string, debugPaths bool) (*ignoreEngine, error) {
+	var rules []rule
+
+	add := func(origin, originDir string, text []string) {
+		for i, line := range text {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+
+			negate := strings.HasPrefix(trimmed, "!")
+			pattern := strings.TrimPrefix(trimmed, "!")
+
+			rules = append(rules, rule{
+				source:    ruleSource{origin: origin, line: i + 1, text: line},
+				pattern:   pattern,
+				negate:    negate,
+				matcher:   ignore.CompileIgnoreLines(pattern),
+				originDir: originDir,
+			})
+		}
+	}
+
+	gitignoreFiles, err := findGitignoreFiles(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range gitignoreFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue // A file we could stat but not read is skipped, not fatal.
+		}
+		add(path, filepath.Dir(path), strings.Split(string(content), "\n"))
+	}
+
+	if ignoreFile != "" {
+		content, err := os.ReadFile(ignoreFile)
+		if err != nil {
+			return nil, err
+		}
+		add(ignoreFile, "", strings.Split(string(content), "\n"))
+	}
+
+	if cliPatterns != "" {
+		add("--ignore", "", strings.Split(cliPatterns, ","))
+	}
+
+	return &ignoreEngine{rules: rules, debugPaths: debugPaths}, nil
+}
+
+// findGitignoreFiles returns every .gitignore file at or below srcDir,
+// ordered root-most first so deeper files are layered on top of (and can
+// override via negation) the ones above them.
+func findGitignoreFiles(srcDir string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == ".gitignore" {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return strings.Count(found[i], string(filepath.Separator)) < strings.Count(found[j], string(filepath.Separator))
+	})
+	return found, nil
+}
+
+// Match reports whether path should be ignored, applying rules in order so
+// a later rule always wins — including a "!" rule re-including a path an
+// earlier rule excluded. When debug logging is enabled, it logs the source
+// and line of whichever rule last decided path's fate.
+func (e *ignoreEngine) Match(path string) bool {
+	ignored := false
+	var decided *rule
+
+	for i := range e.rules {
+		r := &e.rules[i]
+
+		matchPath := path
+		if r.originDir != "" {
+			rel, err := filepath.Rel(r.originDir, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue // path isn't under this .gitignore's own directory.
+			}
+			matchPath = filepath.ToSlash(rel)
+		}
+
+		if r.matcher.MatchesPath(matchPath) {
+			ignored = !r.negate
+			decided = r
+		}
+	}
+
+	if e.debugPaths && decided != nil {
+		verb := "excludes"
+		if decided.negate {
+			verb = "re-includes"
+		}
+		log.Printf("ignore: %s:%d %q %s %s", decided.source.origin, decided.source.line, decided.source.text, verb, path)
+	}
+
+	return ignored
+}
+
+// MayReincludeDescendant reports whether any negation rule could plausibly
+// match something under dir, so the walker knows it must keep descending
+// into an otherwise-excluded directory rather than pruning it outright.
+func (e *ignoreEngine) MayReincludeDescendant(dir string) bool {
+	relDir := filepath.ToSlash(dir)
+	for _, r := range e.rules {
+		if !r.negate {
+			continue
+		}
+		if mayTargetDescendant(r.pattern, relDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayTargetDescendant approximates whether pattern (a gitignore glob, minus
+// its leading "!") could match some path under dir. It compares pattern's
+// directory segments against the tail of dir's own path, treating "*" and
+// "**" as wildcards; a pattern with no slash can apply at any depth per
+// gitignore rule 6 and is always considered a potential match.
+func mayTargetDescendant(pattern, dir string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	segs := strings.Split(pattern, "/")
+	if len(segs) == 1 {
+		return true
+	}
+
+	dirSegs := strings.Split(dir, "/")
+	patDirSegs := segs[:len(segs)-1]
+	offset := len(dirSegs) - len(patDirSegs)
+
+	for i, seg := range patDirSegs {
+		if seg == "**" || seg == "*" {
+			return true
+		}
+		di := offset + i
+		if di < 0 || di >= len(dirSegs) {
+			continue
+		}
+		if seg != dirSegs[di] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestIgnoreEngineScopesRulesToOwnDirectory verifies that a .gitignore rule
+// only applies under its own directory, matching real git semantics: a
+// pattern in a/.gitignore must not reach into a sibling directory b/.
+func TestIgnoreEngineScopesRulesToOwnDirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("could not create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", ".gitignore"), []byte("local.txt\n"), 0o644); err != nil {
+		t.Fatalf("could not write .gitignore: %v", err)
+	}
+
+	engine, err := newIgnoreEngine(root, "", "", false)
+	if err != nil {
+		t.Fatalf("newIgnoreEngine failed: %v", err)
+	}
+
+	if !engine.Match(filepath.Join(root, "a", "local.txt")) {
+		t.Errorf("expected a/local.txt to be ignored by a/.gitignore")
+	}
+	if engine.Match(filepath.Join(root, "b", "local.txt")) {
+		t.Errorf("expected b/local.txt to be unaffected by a/.gitignore's rules")
+	}
+}
+
+// TestIgnoreEngineNegationReincludes verifies that a later "!pattern" line
+// re-includes a path an earlier rule in the same .gitignore excluded,
+// mirroring git's own within-file precedence.
+func TestIgnoreEngineNegationReincludes(t *testing.T) {
+	root := t.TempDir()
+	gitignore := "*.log\n!keep.log\n"
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(gitignore), 0o644); err != nil {
+		t.Fatalf("could not write .gitignore: %v", err)
+	}
+
+	engine, err := newIgnoreEngine(root, "", "", false)
+	if err != nil {
+		t.Fatalf("newIgnoreEngine failed: %v", err)
+	}
+
+	if !engine.Match(filepath.Join(root, "app.log")) {
+		t.Errorf("expected app.log to be ignored by *.log")
+	}
+	if engine.Match(filepath.Join(root, "keep.log")) {
+		t.Errorf("expected keep.log to be re-included by the later !keep.log rule")
+	}
+}
+
+// TestIgnoreEngineLayersCliOverIgnoreFileOverGitignore verifies the layer
+// order documented on newIgnoreEngine: .gitignore rules first, an
+// --ignore-file layered on top, and --ignore (cliPatterns) layered last so
+// it always has the final say, even over a later file's negation.
+func TestIgnoreEngineLayersCliOverIgnoreFileOverGitignore(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("secret.txt\n"), 0o644); err != nil {
+		t.Fatalf("could not write .gitignore: %v", err)
+	}
+
+	ignoreFilePath := filepath.Join(root, ".projectstructignore")
+	if err := os.WriteFile(ignoreFilePath, []byte("!secret.txt\n"), 0o644); err != nil {
+		t.Fatalf("could not write ignore file: %v", err)
+	}
+
+	// Without CLI patterns, the ignore-file's negation re-includes the path
+	// the .gitignore rule excluded, since it's layered above it.
+	engine, err := newIgnoreEngine(root, ignoreFilePath, "", false)
+	if err != nil {
+		t.Fatalf("newIgnoreEngine failed: %v", err)
+	}
+	if engine.Match(filepath.Join(root, "secret.txt")) {
+		t.Errorf("expected --ignore-file's negation to re-include secret.txt")
+	}
+
+	// With a CLI pattern re-excluding the same path, CLI rules are layered
+	// last and must win over the ignore-file's negation.
+	engine, err = newIgnoreEngine(root, ignoreFilePath, "secret.txt", false)
+	if err != nil {
+		t.Fatalf("newIgnoreEngine failed: %v", err)
+	}
+	if !engine.Match(filepath.Join(root, "secret.txt")) {
+		t.Errorf("expected --ignore to have the final say over --ignore-file's negation")
+	}
+}
@@ -13,9 +13,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	builder "pizza-nz/project-struct-cli/builders"
 	"pizza-nz/project-struct-cli/templates"
+
+	lexers "github.com/alecthomas/chroma/v2/lexers"
 )
 
 // Config holds all the configuration parameters for the application,
@@ -34,6 +38,39 @@ type Config struct {
 	MaxSizeKB int64
 	// MaxTotalSizeMB specifies the maximum total size of all files in MB.
 	MaxTotalSizeMB int64
+	// TemplatePath, if set, overrides the embedded template for Format with
+	// a user-supplied file on disk.
+	TemplatePath string
+	// PartialsDir, if set, is the directory to auto-load *.tmpl partials
+	// from alongside TemplatePath. Defaults to TemplatePath's directory.
+	PartialsDir string
+	// Dev reloads templates from the "templates" source directory on every
+	// Build() instead of using the embedded copies, so template edits are
+	// visible without rebuilding the binary.
+	Dev bool
+	// Theme is the Chroma style used to highlight source in -format html.
+	Theme string
+	// IgnoreFile, if set, is an additional ignore file (e.g.
+	// .projectstructignore) layered above the discovered .gitignore files.
+	IgnoreFile string
+	// DebugIgnore logs which rule decided the fate of every skipped path.
+	DebugIgnore bool
+	// Tree renders a file-tree section at the top of the document.
+	Tree bool
+	// SortBy orders files before Build: "path" (default), "size", or "lang".
+	SortBy string
+	// TokenBudget, if > 0, caps -format llm output at that many estimated
+	// tokens, truncating lower-priority files first. See PriorityGlob.
+	TokenBudget int
+	// Model names the target LLM for TokenBudget (e.g. "gpt-4o"). Informational;
+	// the estimator is currently the same heuristic regardless of Model.
+	Model string
+	// PriorityGlob is a comma-separated list of filepath.Match patterns
+	// (e.g. "cmd/*,README.md") kept first when TokenBudget forces truncation.
+	PriorityGlob string
+	// Jobs is how many worker goroutines Walk uses to read and process
+	// files concurrently. <= 0 falls back to runtime.NumCPU().
+	Jobs int
 }
 
 // --- Main Application Logic ---
@@ -46,11 +83,21 @@ func run(cfg Config, output io.Writer) error {
 	case "review":
 		build = builder.NewReviewBuilder()
 	case "llm":
-		build = builder.NewLLMBuilder()
+		llmBuild := builder.NewLLMBuilder()
+		if cfg.TokenBudget > 0 {
+			llmBuild.WithTokenBudget(cfg.TokenBudget, cfg.Model)
+			llmBuild.SetPriorityGlobs(splitList(cfg.PriorityGlob))
+		}
+		build = llmBuild
+	case "html":
+		htmlBuild := builder.NewHTMLBuilder()
+		htmlBuild.SetTheme(cfg.Theme)
+		build = htmlBuild
 	default: // "default"
 		build = builder.NewDefaultBuilder()
 	}
 	build.SetProjectName(filepath.Base(cfg.SrcDir))
+	build.SetLoader(loaderFromConfig(cfg))
 
 	readmePath := filepath.Join(cfg.SrcDir, "README.md")
 	if content, err := os.ReadFile(readmePath); err == nil {
@@ -67,8 +114,13 @@ func run(cfg Config, output io.Writer) error {
 		WithBuilder(build),
 		WithSrcDir(cfg.SrcDir),
 		WithCliIgnore(cfg.IgnoreCli),
+		WithIgnoreFile(cfg.IgnoreFile),
+		WithDebugIgnore(cfg.DebugIgnore),
 		WithMaxSize(cfg.MaxSizeKB),
 		WithTotalSizeLimit(cfg.MaxTotalSizeMB),
+		WithFileTree(cfg.Tree),
+		WithSortBy(cfg.SortBy),
+		WithJobs(cfg.Jobs),
 	)
 
 	// Walk the directory tree and collect file data.
@@ -94,11 +146,28 @@ func main() {
 	flag.StringVar(&cfg.SrcDir, "src", ".", "The source directory to scan.")
 	flag.StringVar(&cfg.OutputFile, "out", "project_structure.md", "The name of the output document.")
 	flag.StringVar(&cfg.IgnoreCli, "ignore", ".idea,node_modules,vendor,build,dist", "Comma-separated list of file patterns to ignore.")
-	flag.StringVar(&cfg.Format, "format", "default", "The output format for the document (e.g., default, review, llm).")
+	flag.StringVar(&cfg.Format, "format", "default", "The output format for the document (e.g., default, review, llm, html).")
 	flag.Int64Var(&cfg.MaxSizeKB, "max-size", 2048, "Maximum individual file size in KB to include (e.g., 2048 for 2MB).")
 	flag.Int64Var(&cfg.MaxTotalSizeMB, "max-total", 100, "Maximum total size of all files in MB.")
+	flag.StringVar(&cfg.TemplatePath, "template", "", "Path to a custom template file that overrides the embedded one for -format.")
+	flag.StringVar(&cfg.PartialsDir, "partials", "", "Directory to auto-load *.tmpl partials from (defaults to -template's directory).")
+	flag.BoolVar(&cfg.Dev, "dev", false, "Reload templates from the templates/ source directory on every build instead of the embedded copies.")
+	flag.StringVar(&cfg.Theme, "theme", "monokai", "Chroma style used to highlight source in -format html.")
+	flag.StringVar(&cfg.IgnoreFile, "ignore-file", "", "Path to an additional ignore file (e.g. .projectstructignore) layered above discovered .gitignore files.")
+	flag.BoolVar(&cfg.DebugIgnore, "debug-ignore", false, "Log which rule decided the fate of every skipped path.")
+	flag.BoolVar(&cfg.Tree, "tree", true, "Render a file-tree section at the top of the document.")
+	noTree := flag.Bool("no-tree", false, "Disable the file-tree section (shorthand for -tree=false).")
+	flag.StringVar(&cfg.SortBy, "sort", "path", "How to order files before building: path, size, or lang.")
+	flag.IntVar(&cfg.TokenBudget, "token-budget", 0, "Cap -format llm output at this many estimated tokens (0 disables the check).")
+	flag.StringVar(&cfg.Model, "model", "gpt-4o", "Target LLM for -token-budget.")
+	flag.StringVar(&cfg.PriorityGlob, "priority-glob", "", "Comma-separated filepath.Match patterns kept first when -token-budget forces truncation.")
+	flag.IntVar(&cfg.Jobs, "jobs", runtime.NumCPU(), "Number of worker goroutines used to read and process files concurrently.")
 	flag.Parse()
 
+	if *noTree {
+		cfg.Tree = false
+	}
+
 	f, err := os.Create(cfg.OutputFile)
 	if err != nil {
 		log.Fatalf("Failed to create output file: %v", err)
@@ -112,6 +181,36 @@ func main() {
 	fmt.Printf("\nSuccess! Project structure written to %s\n", cfg.OutputFile)
 }
 
+// loaderFromConfig returns the templates.Loader requested by cfg's
+// -template/-partials/-dev flags, or nil to use the embedded default.
+// -template takes precedence over -dev when both are set.
+func loaderFromConfig(cfg Config) templates.Loader {
+	if cfg.TemplatePath != "" {
+		dir := cfg.PartialsDir
+		if dir == "" {
+			dir = filepath.Dir(cfg.TemplatePath)
+		}
+		override := filepath.Base(cfg.TemplatePath)
+		if rel, err := filepath.Rel(dir, cfg.TemplatePath); err == nil {
+			override = rel
+		}
+		return &templates.FSLoader{Dir: dir, Override: override}
+	}
+	if cfg.Dev {
+		return templates.NewFSLoader("templates")
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value into its parts, dropping
+// empty entries so an unset flag yields a nil slice rather than [""].
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
 // --- Helper Functions ---
 
 // getFileLanguage determines a file's programming language based on its extension.
@@ -140,8 +239,12 @@ func getFileLanguage(path string) string {
 	case ".sh":
 		return "shell"
 	default:
-		// Return an empty string for unknown types, so Markdown will not
-		// try to apply syntax highlighting.
+		// Fall back to Chroma's filename-based lexer matching for
+		// extensions we don't hardcode above (Rust, Ruby, Kotlin, Zig, etc.)
+		// so they still get a language tag.
+		if lexer := lexers.Match(path); lexer != nil {
+			return strings.ToLower(lexer.Config().Name)
+		}
 		return ""
 	}
 }
@@ -0,0 +1,95 @@
+package templates
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// langByExt maps file extensions to the language name used by the lang
+// template helper. It intentionally mirrors main.getFileLanguage's hardcoded
+// extensions rather than importing it, since templates must not depend on
+// package main; getFileLanguage's Chroma fallback is not applied here.
+var langByExt = map[string]string{
+	".go":   "go",
+	".js":   "javascript",
+	".ts":   "typescript",
+	".py":   "python",
+	".md":   "markdown",
+	".json": "json",
+	".html": "html",
+	".css":  "css",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".sh":   "shell",
+}
+
+// FuncMap is the set of helper functions made available to every template
+// executed by this package, both the embedded defaults and user-supplied
+// templates loaded via Loader. Keeping a single shared FuncMap means a
+// custom --template file can rely on the same helpers the embedded
+// templates use.
+var FuncMap = map[string]any{
+	"lower":      strings.ToLower,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"basename":   filepath.Base,
+	"ext":        filepath.Ext,
+	"lang":       langForPath,
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"indent":     indent,
+	"codefence":  codefence,
+	"default":    defaultValue,
+	"truncate":   truncate,
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+}
+
+// langForPath returns the language name for a given file path based on its
+// extension, or an empty string if the extension is unrecognised.
+func langForPath(path string) string {
+	return langByExt[filepath.Ext(path)]
+}
+
+// indent prefixes every line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codefence wraps content in a Markdown fenced code block tagged with lang.
+func codefence(lang, content string) string {
+	return fmt.Sprintf("```%s\n%s\n```", lang, content)
+}
+
+// defaultValue returns d if v is nil or the zero value for its type
+// (including an empty string, slice, map or array), otherwise it returns v.
+func defaultValue(d, v any) any {
+	if v == nil {
+		return d
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if rv.Len() == 0 {
+			return d
+		}
+	default:
+		if rv.IsZero() {
+			return d
+		}
+	}
+	return v
+}
+
+// truncate shortens s to at most n runes, appending an ellipsis if it was cut.
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
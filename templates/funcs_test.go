@@ -0,0 +1,41 @@
+package templates
+
+import "testing"
+
+// TestDefaultValueZeroValues verifies that default falls back not just for
+// an empty string or nil, but for any other zero-value input (an empty
+// slice, in particular, which template authors pass for optional repeated
+// fields).
+func TestDefaultValueZeroValues(t *testing.T) {
+	cases := []struct {
+		name string
+		v    any
+		want any
+	}{
+		{"empty string", "", "fallback"},
+		{"non-empty string", "x", "x"},
+		{"nil", nil, "fallback"},
+		{"empty slice", []string{}, "fallback"},
+		{"nil slice", []string(nil), "fallback"},
+		{"non-empty slice", []string{"a"}, []string{"a"}},
+		{"zero int", 0, "fallback"},
+		{"non-zero int", 7, 7},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := defaultValue("fallback", c.v)
+			gotSlice, gotIsSlice := got.([]string)
+			wantSlice, wantIsSlice := c.want.([]string)
+			if gotIsSlice || wantIsSlice {
+				if gotIsSlice != wantIsSlice || len(gotSlice) != len(wantSlice) {
+					t.Fatalf("defaultValue(%q, %#v) = %#v, want %#v", "fallback", c.v, got, c.want)
+				}
+				return
+			}
+			if got != c.want {
+				t.Fatalf("defaultValue(%q, %#v) = %#v, want %#v", "fallback", c.v, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Loader resolves a TemplatePath to a parsed *template.Template, including
+// any partials it depends on. This indirection is what lets --template and
+// --dev swap in templates from the local filesystem without changing how
+// builders execute them.
+type Loader interface {
+	// Load parses and returns the named template, ready for Execute.
+	Load(path TemplatePath) (*template.Template, error)
+}
+
+// EmbedLoader loads templates from the binary's embedded TemplatesFS. This
+// is the default Loader and preserves the tool's self-contained behaviour.
+type EmbedLoader struct{}
+
+// NewEmbedLoader returns a Loader backed by the embedded template files.
+func NewEmbedLoader() *EmbedLoader {
+	return &EmbedLoader{}
+}
+
+// Load implements Loader.
+func (l *EmbedLoader) Load(path TemplatePath) (*template.Template, error) {
+	templateBytes, err := TemplatesFS.ReadFile(path.String())
+	if err != nil {
+		return nil, fmt.Errorf("could not read embedded template %s: %w", path, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path.String())).Funcs(FuncMap).Parse(string(templateBytes))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// FSLoader loads templates from a directory on disk, re-reading them from
+// disk on every Load call so edits take effect immediately (used by --dev).
+// When Override is set, it is used as the template's filename instead of
+// the requested TemplatePath — this is how --template swaps in a single
+// external file while still resolving its partials relative to Dir.
+//
+// Any other *.tmpl file found in Dir is auto-loaded as a partial, associated
+// with the returned template under its own base name, mirroring how
+// suti/pagr resolves partials that share a directory with the main layout.
+type FSLoader struct {
+	Dir      string
+	Override string
+}
+
+// NewFSLoader returns a Loader that reads templates and partials from dir.
+func NewFSLoader(dir string) *FSLoader {
+	return &FSLoader{Dir: dir}
+}
+
+// Load implements Loader.
+func (l *FSLoader) Load(path TemplatePath) (*template.Template, error) {
+	name := path.String()
+	if l.Override != "" {
+		name = l.Override
+	}
+
+	mainBytes, err := os.ReadFile(filepath.Join(l.Dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("could not read template %s: %w", name, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(name)).Funcs(FuncMap).Parse(string(mainBytes))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse template %s: %w", name, err)
+	}
+
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read partials directory %s: %w", l.Dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") || entry.Name() == filepath.Base(name) {
+			continue
+		}
+
+		partialBytes, err := os.ReadFile(filepath.Join(l.Dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read partial %s: %w", entry.Name(), err)
+		}
+		if _, err := tmpl.New(entry.Name()).Parse(string(partialBytes)); err != nil {
+			return nil, fmt.Errorf("could not parse partial %s: %w", entry.Name(), err)
+		}
+	}
+
+	return tmpl, nil
+}
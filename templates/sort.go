@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortFiles sorts files in place using the requested strategy. An empty or
+// unrecognised by falls back to "path" (directory-first, then
+// lexicographic), which is what every builder applies before Build() so
+// output is reproducible across runs and filesystems rather than following
+// filepath.WalkDir's incidental order.
+func SortFiles(files []FileData, by string) {
+	switch by {
+	case "size":
+		sort.SliceStable(files, func(i, j int) bool {
+			return len(files[i].Content) < len(files[j].Content)
+		})
+	case "lang":
+		sort.SliceStable(files, func(i, j int) bool {
+			if files[i].Language != files[j].Language {
+				return files[i].Language < files[j].Language
+			}
+			return comparePaths(files[i].Path, files[j].Path) < 0
+		})
+	default: // "path"
+		sort.SliceStable(files, func(i, j int) bool {
+			return comparePaths(files[i].Path, files[j].Path) < 0
+		})
+	}
+}
+
+// comparePaths orders two paths directory-first: at the first segment
+// where one path ends (a file) and the other continues (a directory), the
+// directory sorts first, matching how tree(1) groups directories before
+// their sibling files.
+func comparePaths(a, b string) int {
+	as := strings.Split(filepath.ToSlash(a), "/")
+	bs := strings.Split(filepath.ToSlash(b), "/")
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aIsLeaf, bIsLeaf := i == len(as)-1, i == len(bs)-1
+		if aIsLeaf != bIsLeaf {
+			if aIsLeaf {
+				return 1
+			}
+			return -1
+		}
+		if as[i] != bs[i] {
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
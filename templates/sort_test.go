@@ -0,0 +1,80 @@
+package templates
+
+import "testing"
+
+func filesOf(paths ...string) []FileData {
+	files := make([]FileData, len(paths))
+	for i, p := range paths {
+		files[i] = FileData{Path: p}
+	}
+	return files
+}
+
+func pathsOf(files []FileData) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// TestSortFilesPathIsDirectoryFirst verifies the default "path" strategy
+// groups directories before their sibling files at each level, matching
+// how tree(1) orders entries, rather than plain lexicographic order.
+func TestSortFilesPathIsDirectoryFirst(t *testing.T) {
+	files := filesOf("root.txt", "a/nested.go", "b.txt")
+	SortFiles(files, "path")
+
+	got := pathsOf(files)
+	want := []string{"a/nested.go", "b.txt", "root.txt"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("SortFiles(path) = %v, want %v", got, want)
+		}
+	}
+}
+
+// TestSortFilesSize verifies the "size" strategy orders by content length.
+func TestSortFilesSize(t *testing.T) {
+	files := []FileData{
+		{Path: "big.txt", Content: "aaaaaaaaaa"},
+		{Path: "small.txt", Content: "a"},
+	}
+	SortFiles(files, "size")
+
+	got := pathsOf(files)
+	if got[0] != "small.txt" || got[1] != "big.txt" {
+		t.Fatalf("SortFiles(size) = %v, want [small.txt big.txt]", got)
+	}
+}
+
+// TestSortFilesLang verifies the "lang" strategy groups by language first,
+// falling back to path order within a language.
+func TestSortFilesLang(t *testing.T) {
+	files := []FileData{
+		{Path: "b.go", Language: "go"},
+		{Path: "a.py", Language: "python"},
+		{Path: "a.go", Language: "go"},
+	}
+	SortFiles(files, "lang")
+
+	got := pathsOf(files)
+	want := []string{"a.go", "b.go", "a.py"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("SortFiles(lang) = %v, want %v", got, want)
+		}
+	}
+}
+
+// TestSortFilesUnknownFallsBackToPath verifies an empty/unrecognised
+// strategy falls back to "path" ordering.
+func TestSortFilesUnknownFallsBackToPath(t *testing.T) {
+	files := filesOf("root.txt", "a/nested.go")
+	SortFiles(files, "bogus")
+
+	got := pathsOf(files)
+	if got[0] != "a/nested.go" || got[1] != "root.txt" {
+		t.Fatalf("SortFiles(bogus) = %v, want directory-first fallback", got)
+	}
+}
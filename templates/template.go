@@ -3,10 +3,7 @@ package templates
 import (
 	"bytes"
 	"embed"
-	"fmt"
 	"io"
-	"path/filepath"
-	"text/template"
 )
 
 // TemplatesFS holds the embedded template files for generating the documents.
@@ -26,6 +23,7 @@ const (
 	Review  TemplatePath = "review.md.tmpl"
 	Default TemplatePath = "default.md.tmpl"
 	LLM     TemplatePath = "llm.txt.tmpl"
+	HTML    TemplatePath = "html.tmpl"
 )
 
 func (p TemplatePath) String() string {
@@ -35,8 +33,11 @@ func (p TemplatePath) String() string {
 // TemplateData is the data structure passed to the templates for execution.
 type TemplateData struct {
 	ProjectName    string
-	ProjectSummary string
-	Files          []FileData
+	ProjectSummary FileData
+	// FileTree is the rendered ASCII tree (see TreeNode.Render), or empty
+	// when the tree section is disabled.
+	FileTree string
+	Files    []FileData
 }
 
 // FileData represents the contents of a single source file.
@@ -47,22 +48,24 @@ type FileData struct {
 	Content string
 	// Language is the detected programming language based on the file extension.
 	Language string
+	// Tokens is the estimated LLM token count for Content, as computed by
+	// the Generator's Tokenizer. Zero if never estimated.
+	Tokens int
 }
 
-func ExecuteTemplate(templatePath TemplatePath, data any) (io.Reader, error) {
-	// Read the embedded template file.
-	templateBytes, err := TemplatesFS.ReadFile(templatePath.String())
-	if err != nil {
-		return nil, fmt.Errorf("could not read embedded template %s: %w", templatePath, err)
+// ExecuteTemplate loads templatePath via loader and executes it against
+// data. Passing nil for loader falls back to the embedded templates, which
+// keeps the tool self-contained when no override has been configured.
+func ExecuteTemplate(loader Loader, templatePath TemplatePath, data any) (io.Reader, error) {
+	if loader == nil {
+		loader = NewEmbedLoader()
 	}
 
-	// Parse the template.
-	tmpl, err := template.New(filepath.Base(templatePath.String())).Parse(string(templateBytes))
+	tmpl, err := loader.Load(templatePath)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse template: %w", err)
+		return nil, err
 	}
 
-	// Execute the template into a buffer.
 	var doc bytes.Buffer
 	if err := tmpl.Execute(&doc, data); err != nil {
 		return nil, err
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"math"
+	"unicode"
+)
+
+// Tokenizer estimates how many LLM tokens a piece of source text would
+// consume. It is an interface so a real BPE implementation (e.g. a
+// tiktoken-compatible encoder) can be swapped in for a specific model
+// without touching callers that only need an estimate.
+type Tokenizer interface {
+	CountTokens(content string) int
+}
+
+// HeuristicTokenizer estimates tokens at roughly 4 characters per token,
+// the common rule of thumb for English text and source code, adjusted for
+// whitespace-heavy files (more tokens per character, since most tokenizers
+// split on runs of whitespace) and dense/minified files (fewer, longer
+// tokens per character).
+type HeuristicTokenizer struct{}
+
+// CountTokens returns the estimated token count for content.
+func (HeuristicTokenizer) CountTokens(content string) int {
+	if content == "" {
+		return 0
+	}
+
+	var whitespace int
+	for _, r := range content {
+		if unicode.IsSpace(r) {
+			whitespace++
+		}
+	}
+	ratio := float64(whitespace) / float64(len(content))
+
+	charsPerToken := 4.0
+	switch {
+	case ratio > 0.25:
+		charsPerToken = 3.0 // whitespace-heavy: whitespace runs become their own tokens
+	case ratio < 0.08:
+		charsPerToken = 5.0 // dense/minified: long identifiers and operators pack more per token
+	}
+
+	return int(math.Ceil(float64(len(content)) / charsPerToken))
+}
@@ -0,0 +1,94 @@
+package templates
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// TreeNode is a single entry in the accepted-file hierarchy built by
+// Generator after Walk() completes. Builders render it as an ASCII tree
+// (like tree(1)) at the top of the document.
+type TreeNode struct {
+	// Name is the node's own path segment (e.g. "main.go" or "builders").
+	Name string
+	// Path is the node's full relative path from the scan root; empty for
+	// the root node itself.
+	Path string
+	// IsDir reports whether this node represents a directory.
+	IsDir bool
+	// Size is the file's content size in bytes; zero for directories.
+	Size int64
+	// Language is the file's detected language; empty for directories.
+	Language string
+	// Children holds the node's direct descendants, directory-first and
+	// lexicographic, matching the order SortFiles produces for a flat list.
+	Children []*TreeNode
+}
+
+// NewFileTree builds the directory hierarchy for files into a single root
+// TreeNode named rootName. files is expected to already be in the order the
+// tree should display siblings in (see SortFiles).
+func NewFileTree(rootName string, files []FileData) *TreeNode {
+	root := &TreeNode{Name: rootName, IsDir: true}
+	dirs := map[string]*TreeNode{"": root}
+
+	for _, file := range files {
+		parts := strings.Split(filepath.ToSlash(file.Path), "/")
+		parent := root
+		accumPath := ""
+
+		for i, part := range parts {
+			if accumPath == "" {
+				accumPath = part
+			} else {
+				accumPath = accumPath + "/" + part
+			}
+
+			isLeaf := i == len(parts)-1
+			if isLeaf {
+				parent.Children = append(parent.Children, &TreeNode{
+					Name:     part,
+					Path:     accumPath,
+					Size:     int64(len(file.Content)),
+					Language: file.Language,
+				})
+				continue
+			}
+
+			dir, exists := dirs[accumPath]
+			if !exists {
+				dir = &TreeNode{Name: part, Path: accumPath, IsDir: true}
+				parent.Children = append(parent.Children, dir)
+				dirs[accumPath] = dir
+			}
+			parent = dir
+		}
+	}
+
+	return root
+}
+
+// Render renders the tree as ASCII art in the style of the tree(1) command.
+func (n *TreeNode) Render() string {
+	var b strings.Builder
+	b.WriteString(n.Name)
+	b.WriteString("\n")
+	renderChildren(&b, n.Children, "")
+	return b.String()
+}
+
+func renderChildren(b *strings.Builder, children []*TreeNode, prefix string) {
+	for i, child := range children {
+		last := i == len(children)-1
+
+		connector, childPrefix := "├── ", prefix+"│   "
+		if last {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+
+		b.WriteString(prefix + connector + child.Name + "\n")
+		if len(child.Children) > 0 {
+			renderChildren(b, child.Children, childPrefix)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewFileTreeBuildsHierarchy verifies files sharing a directory prefix
+// are grouped under one shared TreeNode rather than duplicated per file.
+func TestNewFileTreeBuildsHierarchy(t *testing.T) {
+	files := filesOf("a/one.go", "a/two.go", "b.txt")
+	root := NewFileTree("root", files)
+
+	if root.Name != "root" || !root.IsDir {
+		t.Fatalf("root node = %+v, want IsDir root named %q", root, "root")
+	}
+	if len(root.Children) != 2 {
+		t.Fatalf("root has %d children, want 2 (a/, b.txt)", len(root.Children))
+	}
+
+	dirA := root.Children[0]
+	if dirA.Name != "a" || !dirA.IsDir {
+		t.Fatalf("first child = %+v, want directory %q", dirA, "a")
+	}
+	if len(dirA.Children) != 2 {
+		t.Fatalf("a/ has %d children, want 2 (one.go, two.go)", len(dirA.Children))
+	}
+
+	leaf := root.Children[1]
+	if leaf.Name != "b.txt" || leaf.IsDir {
+		t.Fatalf("second child = %+v, want leaf file %q", leaf, "b.txt")
+	}
+}
+
+// TestTreeNodeRenderASCII verifies Render draws tree(1)-style connectors,
+// including the last-sibling "└──" vs. "├──" distinction.
+func TestTreeNodeRenderASCII(t *testing.T) {
+	files := filesOf("a/one.go", "b.txt")
+	rendered := NewFileTree("root", files).Render()
+
+	for _, want := range []string{"root\n", "├── a\n", "│   └── one.go\n", "└── b.txt\n"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("Render() = %q, missing %q", rendered, want)
+		}
+	}
+}